@@ -0,0 +1,157 @@
+/*
+ * Copyright 2020 Mark Lakes
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package mongoclt
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultBulkByteThreshold = 16 * 1024 * 1024 // mirror mongo's own 16MB max bson/batch size
+)
+
+// BufferedBulk queues insert/update/delete operations locally and flushes them
+// to the server in a single BulkWrite once a configurable operation count or
+// byte-size threshold is reached, for high-throughput ingest without one
+// round-trip per document. This mirrors the buffered-bulk pattern used in
+// mongo's own gotools (mongoimport/mongorestore).
+type BufferedBulk struct {
+	clt       *Client
+	entity    string
+	size      int
+	byteLimit int
+	ordered   bool
+
+	models []mongo.WriteModel
+	bytes  int
+}
+
+// BulkOption specifies an option for NewBulk
+type BulkOption struct {
+	f func(*BufferedBulk)
+}
+
+// BulkByteThreshold overrides the default byte-size threshold (16MB) at which
+// the buffer is auto-flushed
+func BulkByteThreshold(bytes int) BulkOption {
+	return BulkOption{func(b *BufferedBulk) {
+		b.byteLimit = bytes
+	}}
+}
+
+// BulkUnordered allows queued operations to execute out of order and continue
+// past individual failures, rather than stopping at the first error
+func BulkUnordered() BulkOption {
+	return BulkOption{func(b *BufferedBulk) {
+		b.ordered = false
+	}}
+}
+
+// NewBulk returns a BufferedBulk that batches writes to entity, auto-flushing
+// every size queued operations (or the byte threshold, whichever comes first)
+func (clt *Client) NewBulk(entity string, size int, bopts ...BulkOption) *BufferedBulk {
+
+	b := &BufferedBulk{
+		clt:       clt,
+		entity:    entity,
+		size:      size,
+		byteLimit: defaultBulkByteThreshold,
+		ordered:   true,
+		models:    make([]mongo.WriteModel, 0, size),
+	}
+	for _, option := range bopts {
+		option.f(b)
+	}
+
+	return b
+}
+
+// Insert queues a document for insertion
+func (b *BufferedBulk) Insert(ctx context.Context, doc map[string]interface{}) error {
+	return b.queue(ctx, mongo.NewInsertOneModel().SetDocument(doc), doc)
+}
+
+// Update queues an update of the first document matching filter
+func (b *BufferedBulk) Update(ctx context.Context, filter bson.M, update bson.M) error {
+	model := mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(bson.M{"$set": update})
+	return b.queue(ctx, model, filter, update)
+}
+
+// Upsert queues an update of the first document matching filter, inserting one
+// if no document matches
+func (b *BufferedBulk) Upsert(ctx context.Context, filter bson.M, update bson.M) error {
+	model := mongo.NewUpdateOneModel().SetFilter(filter).SetUpdate(bson.M{"$set": update}).SetUpsert(true)
+	return b.queue(ctx, model, filter, update)
+}
+
+// Delete queues a deletion of the first document matching filter
+func (b *BufferedBulk) Delete(ctx context.Context, filter bson.M) error {
+	model := mongo.NewDeleteOneModel().SetFilter(filter)
+	return b.queue(ctx, model, filter)
+}
+
+func (b *BufferedBulk) queue(ctx context.Context, model mongo.WriteModel, sized ...interface{}) error {
+
+	size := 0
+	for _, doc := range sized {
+		raw, err := bson.Marshal(doc)
+		if err != nil {
+			return normalizeError(err)
+		}
+		size += len(raw)
+	}
+
+	b.models = append(b.models, model)
+	b.bytes += size
+
+	if len(b.models) >= b.size || b.bytes >= b.byteLimit {
+		return b.Flush(ctx)
+	}
+
+	return nil
+}
+
+// Flush sends any queued operations to the server, returning per-op errors via
+// mongo.BulkWriteException when some (but not all) operations failed
+func (b *BufferedBulk) Flush(ctx context.Context) error {
+
+	if len(b.models) == 0 {
+		return nil
+	}
+
+	coll := b.clt.client.Database(b.clt.opts.dbName).Collection(b.entity)
+	bwOpts := options.BulkWrite().SetOrdered(b.ordered)
+
+	_, err := coll.BulkWrite(ctx, b.models, bwOpts)
+
+	b.models = b.models[:0]
+	b.bytes = 0
+
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return nil
+}
+
+// Close flushes any remaining queued operations
+func (b *BufferedBulk) Close(ctx context.Context) error {
+	return b.Flush(ctx)
+}
@@ -0,0 +1,211 @@
+/*
+ * Copyright 2020 Mark Lakes
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package mongoclt
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// Bucket wraps a mongo-driver GridFS bucket so callers can store and retrieve
+// large files (images, binary blobs, etc.) without importing the raw driver.
+type Bucket struct {
+	clt    *Client
+	bucket *gridfs.Bucket
+}
+
+// BucketOption specifies an option for OpenBucket
+type BucketOption struct {
+	f func(*options.BucketOptions)
+}
+
+// BucketChunkSizeBytes specifies the chunk size used to store file data, in bytes
+func BucketChunkSizeBytes(size int32) BucketOption {
+	return BucketOption{func(bo *options.BucketOptions) {
+		bo.SetChunkSizeBytes(size)
+	}}
+}
+
+// BucketReadPreference specifies the read preference used by the bucket
+func BucketReadPreference(rp *readpref.ReadPref) BucketOption {
+	return BucketOption{func(bo *options.BucketOptions) {
+		bo.SetReadPreference(rp)
+	}}
+}
+
+// OpenBucket returns a Bucket for storing and retrieving files using the named
+// GridFS bucket (the default bucket name is "fs" when name is "")
+func (clt *Client) OpenBucket(name string, bopts ...BucketOption) (*Bucket, error) {
+
+	gopts := options.GridFSBucket()
+	if name != "" {
+		gopts = gopts.SetName(name)
+	}
+	for _, option := range bopts {
+		option.f(gopts)
+	}
+
+	b, err := gridfs.NewBucket(clt.client.Database(clt.opts.dbName), gopts)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return &Bucket{clt: clt, bucket: b}, nil
+}
+
+// Upload reads from r and stores it in the bucket under filename, returning the
+// id of the stored file as a string (hex-encoded for an ObjectID). A custom _id
+// may be supplied via the UploadOption returned by UploadID, mirroring the
+// mongofiles capability; it is returned as rendered by idToString.
+func (b *Bucket) Upload(filename string, r io.Reader, metadata map[string]interface{}, uopts ...UploadOption) (string, error) {
+
+	uo := options.GridFSUpload()
+	if metadata != nil {
+		uo = uo.SetMetadata(metadata)
+	}
+
+	uoo := uploadOptions{}
+	for _, option := range uopts {
+		option.f(&uoo)
+	}
+
+	if uoo.id != nil {
+		err := b.bucket.UploadFromStreamWithID(uoo.id, filename, r, uo)
+		if err != nil {
+			return "", normalizeError(err)
+		}
+		return idToString(uoo.id), nil
+	}
+
+	fileID, err := b.bucket.UploadFromStream(filename, r, uo)
+	if err != nil {
+		return "", normalizeError(err)
+	}
+
+	return idToString(fileID), nil
+}
+
+// UploadOption specifies an option for Upload
+type UploadOption struct {
+	f func(*uploadOptions)
+}
+
+type uploadOptions struct {
+	id interface{}
+}
+
+// UploadID specifies a custom _id to use for the uploaded file rather than
+// letting mongo generate one
+func UploadID(id interface{}) UploadOption {
+	return UploadOption{func(uo *uploadOptions) {
+		uo.id = id
+	}}
+}
+
+// Download writes the contents of the file with the given id (as returned by
+// Upload - a hex ObjectID, or a custom string _id from UploadID) to w. Only
+// ObjectID and string ids can be addressed; see stringToID
+func (b *Bucket) Download(fileID string, w io.Writer) error {
+
+	_, err := b.bucket.DownloadToStream(stringToID(fileID), w)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return nil
+}
+
+// DownloadByName writes the contents of the most recent revision of the file
+// with the given filename to w
+func (b *Bucket) DownloadByName(filename string, w io.Writer) error {
+
+	_, err := b.bucket.DownloadToStreamByName(filename, w)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return nil
+}
+
+// Delete removes the file with the given id (as returned by Upload - a hex
+// ObjectID, or a custom string _id from UploadID), along with its chunks. Only
+// ObjectID and string ids can be addressed; see stringToID
+func (b *Bucket) Delete(fileID string) error {
+
+	if err := b.bucket.Delete(stringToID(fileID)); err != nil {
+		return normalizeError(err)
+	}
+
+	return nil
+}
+
+// Find lists the files stored in the bucket matching the given filter (an empty
+// or nil filter returns every file)
+func (b *Bucket) Find(filter bson.M) ([]map[string]interface{}, error) {
+	ctx, cancel := b.clt.defaultContext()
+	defer cancel()
+	return b.FindCtx(ctx, filter)
+}
+
+// FindCtx is Find, bound to the given context instead of a default timeout
+func (b *Bucket) FindCtx(ctx context.Context, filter bson.M) ([]map[string]interface{}, error) {
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	cursor, err := b.bucket.FindContext(ctx, filter)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return mapsFromResults(results), nil
+}
+
+// idToString renders a GridFS file id (typically a primitive.ObjectID, but
+// custom ids of any type are supported) as a string
+func idToString(id interface{}) string {
+	if oid, ok := id.(primitive.ObjectID); ok {
+		return oid.Hex()
+	}
+	return fmt.Sprintf("%v", id)
+}
+
+// stringToID reverses idToString for ObjectID and string ids: it tries to parse
+// fileID as a hex-encoded ObjectID (the common case, matching what Upload
+// returns by default) and falls back to the raw string for files uploaded with
+// a non-ObjectID custom _id via UploadID. A custom _id of any other type (ex: an
+// int) comes back as a string and does not match the stored file, and a custom
+// string _id that is valid ObjectID hex comes back as an ObjectID
+func stringToID(fileID string) interface{} {
+	if oid, err := primitive.ObjectIDFromHex(fileID); err == nil {
+		return oid
+	}
+	return fileID
+}
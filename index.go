@@ -0,0 +1,178 @@
+/*
+ * Copyright 2020 Mark Lakes
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package mongoclt
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IndexOption specifies an option for CreateIndex
+type IndexOption struct {
+	f func(*options.IndexOptions)
+}
+
+// IndexUnique requires that values for the indexed field(s) be unique across the collection
+func IndexUnique() IndexOption {
+	return IndexOption{func(io *options.IndexOptions) {
+		io.SetUnique(true)
+	}}
+}
+
+// IndexSparse only indexes documents that contain the indexed field(s)
+func IndexSparse() IndexOption {
+	return IndexOption{func(io *options.IndexOptions) {
+		io.SetSparse(true)
+	}}
+}
+
+// IndexPartialFilterExpression only indexes documents matching the given filter
+func IndexPartialFilterExpression(filter bson.M) IndexOption {
+	return IndexOption{func(io *options.IndexOptions) {
+		io.SetPartialFilterExpression(filter)
+	}}
+}
+
+// IndexExpireAfterSeconds creates a TTL index that removes documents seconds
+// after the indexed timestamp field
+func IndexExpireAfterSeconds(seconds int32) IndexOption {
+	return IndexOption{func(io *options.IndexOptions) {
+		io.SetExpireAfterSeconds(seconds)
+	}}
+}
+
+// IndexCollation specifies the collation to use for string comparisons on the index
+func IndexCollation(collation *options.Collation) IndexOption {
+	return IndexOption{func(io *options.IndexOptions) {
+		io.SetCollation(collation)
+	}}
+}
+
+// IndexName overrides the name mongo would otherwise generate for the index
+func IndexName(name string) IndexOption {
+	return IndexOption{func(io *options.IndexOptions) {
+		io.SetName(name)
+	}}
+}
+
+// IndexModel describes a single index to create via EnsureIndexes. Keys
+// specifies the indexed fields in compound order
+// (ex: bson.D{{Key: "tenant", Value: 1}, {Key: "id", Value: 1}})
+type IndexModel struct {
+	Keys    bson.D
+	Options []IndexOption
+}
+
+// CreateIndex creates an index on the given compound keys for entity and
+// returns the name of the created index
+func (clt *Client) CreateIndex(entity string, keys bson.D, iopts ...IndexOption) (string, error) {
+	ctx, cancel := clt.defaultContext()
+	defer cancel()
+	return clt.CreateIndexCtx(ctx, entity, keys, iopts...)
+}
+
+// CreateIndexCtx is CreateIndex, bound to the given context instead of a default timeout
+func (clt *Client) CreateIndexCtx(ctx context.Context, entity string, keys bson.D, iopts ...IndexOption) (string, error) {
+
+	io := options.Index()
+	for _, option := range iopts {
+		option.f(io)
+	}
+
+	coll := clt.client.Database(clt.opts.dbName).Collection(entity)
+	name, err := coll.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys, Options: io})
+	if err != nil {
+		return "", normalizeError(err)
+	}
+
+	return name, nil
+}
+
+// DropIndex removes the named index from entity
+func (clt *Client) DropIndex(entity, name string) error {
+	ctx, cancel := clt.defaultContext()
+	defer cancel()
+	return clt.DropIndexCtx(ctx, entity, name)
+}
+
+// DropIndexCtx is DropIndex, bound to the given context instead of a default timeout
+func (clt *Client) DropIndexCtx(ctx context.Context, entity, name string) error {
+
+	coll := clt.client.Database(clt.opts.dbName).Collection(entity)
+	_, err := coll.Indexes().DropOne(ctx, name)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return nil
+}
+
+// ListIndexes returns the index specifications currently defined on entity
+func (clt *Client) ListIndexes(entity string) ([]map[string]interface{}, error) {
+	ctx, cancel := clt.defaultContext()
+	defer cancel()
+	return clt.ListIndexesCtx(ctx, entity)
+}
+
+// ListIndexesCtx is ListIndexes, bound to the given context instead of a default timeout
+func (clt *Client) ListIndexesCtx(ctx context.Context, entity string) ([]map[string]interface{}, error) {
+
+	coll := clt.client.Database(clt.opts.dbName).Collection(entity)
+	cursor, err := coll.Indexes().List(ctx)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return mapsFromResults(results), nil
+}
+
+// EnsureIndexes declaratively ensures every index in models exists on entity,
+// for use at startup rather than managing indexes by hand in the shell
+func (clt *Client) EnsureIndexes(entity string, models []IndexModel) error {
+	ctx, cancel := clt.defaultContext()
+	defer cancel()
+	return clt.EnsureIndexesCtx(ctx, entity, models)
+}
+
+// EnsureIndexesCtx is EnsureIndexes, bound to the given context instead of a default timeout
+func (clt *Client) EnsureIndexesCtx(ctx context.Context, entity string, models []IndexModel) error {
+
+	coll := clt.client.Database(clt.opts.dbName).Collection(entity)
+
+	mongoModels := make([]mongo.IndexModel, 0, len(models))
+	for _, m := range models {
+		io := options.Index()
+		for _, option := range m.Options {
+			option.f(io)
+		}
+		mongoModels = append(mongoModels, mongo.IndexModel{Keys: m.Keys, Options: io})
+	}
+
+	if _, err := coll.Indexes().CreateMany(ctx, mongoModels); err != nil {
+		return normalizeError(err)
+	}
+
+	return nil
+}
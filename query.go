@@ -0,0 +1,176 @@
+/*
+ * Copyright 2020 Mark Lakes
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package mongoclt
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FindOption specifies an option for FindWithFilter
+type FindOption struct {
+	f func(*findOptions)
+}
+
+type findOptions struct {
+	sort       bson.D
+	projection bson.M
+	limit      int64
+	skip       int64
+}
+
+// FindSort specifies the fields and order (1 ascending, -1 descending) to sort results by
+func FindSort(sort bson.D) FindOption {
+	return FindOption{func(fo *findOptions) {
+		fo.sort = sort
+	}}
+}
+
+// FindProjection specifies the fields to include (1) or exclude (0) in returned documents
+func FindProjection(projection bson.M) FindOption {
+	return FindOption{func(fo *findOptions) {
+		fo.projection = projection
+	}}
+}
+
+// FindLimit specifies the maximum number of documents to return
+func FindLimit(limit int64) FindOption {
+	return FindOption{func(fo *findOptions) {
+		fo.limit = limit
+	}}
+}
+
+// FindSkip specifies the number of documents to skip before returning results
+func FindSkip(skip int64) FindOption {
+	return FindOption{func(fo *findOptions) {
+		fo.skip = skip
+	}}
+}
+
+// FindWithFilter finds entries for the specified entity matching the given mongo query
+// document filter. Unlike Find, filter may use any valid query operators
+// (ex: "$gt", "$in", "$regex", "$and", "$or") and typed values rather than just
+// string equality on a single field.
+func (clt *Client) FindWithFilter(entity string, filter bson.M, fopts ...FindOption) ([]interface{}, error) {
+	ctx, cancel := clt.defaultContext()
+	defer cancel()
+	return clt.FindWithFilterCtx(ctx, entity, filter, fopts...)
+}
+
+// FindWithFilterCtx is FindWithFilter, bound to the given context instead of a default timeout
+func (clt *Client) FindWithFilterCtx(ctx context.Context, entity string, filter bson.M, fopts ...FindOption) ([]interface{}, error) {
+
+	fo := findOptions{}
+	for _, option := range fopts {
+		option.f(&fo)
+	}
+
+	coll := clt.client.Database(clt.opts.dbName).Collection(entity)
+
+	mopts := options.Find()
+	if fo.sort != nil {
+		mopts = mopts.SetSort(fo.sort)
+	}
+	if fo.projection != nil {
+		mopts = mopts.SetProjection(fo.projection)
+	}
+	if fo.limit > 0 {
+		mopts = mopts.SetLimit(fo.limit)
+	}
+	if fo.skip > 0 {
+		mopts = mopts.SetSkip(fo.skip)
+	}
+
+	if filter == nil {
+		filter = bson.M{}
+	}
+
+	cursor, err := coll.Find(ctx, filter, mopts)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return docsFromResults(results), nil
+}
+
+// Aggregate runs the specified aggregation pipeline (ex: "$match", "$group", "$lookup",
+// "$project") against the collection for entity and returns the resulting documents.
+func (clt *Client) Aggregate(entity string, pipeline []bson.M) ([]interface{}, error) {
+	ctx, cancel := clt.defaultContext()
+	defer cancel()
+	return clt.AggregateCtx(ctx, entity, pipeline)
+}
+
+// AggregateCtx is Aggregate, bound to the given context instead of a default timeout
+func (clt *Client) AggregateCtx(ctx context.Context, entity string, pipeline []bson.M) ([]interface{}, error) {
+
+	coll := clt.client.Database(clt.opts.dbName).Collection(entity)
+
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []bson.M
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, normalizeError(err)
+	}
+
+	return docsFromResults(results), nil
+}
+
+// docsFromResults converts raw bson results into plain Go maps, replacing any
+// bson/primitive typed values with their native equivalents.
+func docsFromResults(results []bson.M) []interface{} {
+
+	maps := mapsFromResults(results)
+
+	docs := make([]interface{}, 0, len(maps))
+	for _, m := range maps {
+		docs = append(docs, m)
+	}
+
+	return docs
+}
+
+// mapsFromResults converts raw bson results into plain Go maps, replacing any
+// bson/primitive typed values with their native equivalents.
+func mapsFromResults(results []bson.M) []map[string]interface{} {
+
+	docs := make([]map[string]interface{}, 0, len(results))
+	for _, result := range results {
+		res := make(map[string]interface{})
+		// must replace fields that are primitive.A with []interface{}
+		respm := (primitive.M)(result)
+		for key, value := range respm {
+			v := convertToNative(value)
+			res[key] = v
+		}
+		docs = append(docs, res)
+	}
+
+	return docs
+}
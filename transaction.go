@@ -0,0 +1,107 @@
+/*
+ * Copyright 2020 Mark Lakes
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package mongoclt
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// SessionContext wraps a mongo-driver session bound to an in-progress
+// transaction, exposing Client's Create, Update, Delete and Find bound to the
+// session so callers can perform atomic multi-collection updates without
+// dropping to the raw driver.
+type SessionContext struct {
+	clt *Client
+	ctx mongo.SessionContext
+}
+
+// Create or insert a new entry into the collection entity, as part of the transaction
+func (sc SessionContext) Create(entity, keyValue string, valueEntry map[string]interface{}) (*map[string]interface{}, error) {
+	return sc.clt.CreateCtx(sc.ctx, entity, keyValue, valueEntry)
+}
+
+// Update the entry with contents of valueEntry matching the specified id, as part of the transaction
+func (sc SessionContext) Update(entity, id string, valueEntry map[string]interface{}) error {
+	return sc.clt.UpdateCtx(sc.ctx, entity, id, valueEntry)
+}
+
+// Delete the specified entry, as part of the transaction
+func (sc SessionContext) Delete(entity, id string) error {
+	return sc.clt.DeleteCtx(sc.ctx, entity, id)
+}
+
+// Find entry for specified entity where value matches the value in the field, as part of the transaction
+func (sc SessionContext) Find(entity, field, value string) ([]interface{}, error) {
+	return sc.clt.FindCtx(sc.ctx, entity, field, value)
+}
+
+// TxnOption specifies an option for WithTransaction
+type TxnOption struct {
+	f func(*options.TransactionOptions)
+}
+
+// TxnReadConcern specifies the read concern for the transaction (ex: "majority", "snapshot")
+func TxnReadConcern(level string) TxnOption {
+	return TxnOption{func(to *options.TransactionOptions) {
+		to.SetReadConcern(readconcern.New(readconcern.Level(level)))
+	}}
+}
+
+// TxnWriteConcern specifies the write concern for the transaction
+func TxnWriteConcern(w int, journal bool) TxnOption {
+	return TxnOption{func(to *options.TransactionOptions) {
+		to.SetWriteConcern(writeconcern.New(writeconcern.W(w), writeconcern.J(journal)))
+	}}
+}
+
+// TxnReadPreference specifies the read preference for the transaction (ex: readpref.Primary(), readpref.Secondary())
+func TxnReadPreference(rp *readpref.ReadPref) TxnOption {
+	return TxnOption{func(to *options.TransactionOptions) {
+		to.SetReadPreference(rp)
+	}}
+}
+
+// WithTransaction runs fn inside a multi-document transaction, committing if fn
+// returns nil and aborting (and returning the error) otherwise. fn receives a
+// SessionContext bound to the transaction for its CRUD operations.
+func (clt *Client) WithTransaction(ctx context.Context, fn func(sc SessionContext) error, topts ...TxnOption) error {
+
+	txnOpts := options.Transaction()
+	for _, option := range topts {
+		option.f(txnOpts)
+	}
+
+	session, err := clt.client.StartSession()
+	if err != nil {
+		return normalizeError(err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(SessionContext{clt: clt, ctx: sessCtx})
+	}, txnOpts)
+	if err != nil {
+		return normalizeError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+ * Copyright 2020 Mark Lakes
+ *
+ *  Licensed under the Apache License, Version 2.0 (the "License");
+ *  you may not use this file except in compliance with the License.
+ *  You may obtain a copy of the License at
+ *
+ *  http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package mongoclt
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ChangeEvent is a single decoded change stream event (insert/update/replace/delete/etc.)
+type ChangeEvent map[string]interface{}
+
+// WatchOption specifies an option for Watch
+type WatchOption struct {
+	f func(*watchOptions)
+}
+
+type watchOptions struct {
+	resumeToken          bson.Raw
+	startAtOperationTime *primitive.Timestamp
+	fullDocument         bool
+}
+
+// WatchResumeAfter resumes the change stream after the given resume token
+func WatchResumeAfter(token bson.Raw) WatchOption {
+	return WatchOption{func(wo *watchOptions) {
+		wo.resumeToken = token
+	}}
+}
+
+// WatchStartAtOperationTime resumes the change stream at the given cluster time
+func WatchStartAtOperationTime(t, i uint32) WatchOption {
+	return WatchOption{func(wo *watchOptions) {
+		wo.startAtOperationTime = &primitive.Timestamp{T: t, I: i}
+	}}
+}
+
+// WatchFullDocument requests that update events include the full current
+// version of the document ("fullDocument=updateLookup")
+func WatchFullDocument() WatchOption {
+	return WatchOption{func(wo *watchOptions) {
+		wo.fullDocument = true
+	}}
+}
+
+// Watch opens a change stream on the collection for entity (or on the whole
+// database when entity is "") filtered by pipeline, and streams decoded events
+// on the returned channel. The channel is closed when the stream ends or an
+// unrecoverable error occurs; retryable failures are resumed automatically
+// using the last seen resume token.
+func (clt *Client) Watch(ctx context.Context, entity string, pipeline []bson.M, wopts ...WatchOption) (<-chan ChangeEvent, error) {
+
+	wo := watchOptions{}
+	for _, option := range wopts {
+		option.f(&wo)
+	}
+
+	stream, err := clt.openChangeStream(ctx, entity, pipeline, &wo)
+	if err != nil {
+		return nil, normalizeError(err)
+	}
+
+	events := make(chan ChangeEvent)
+	go clt.watchLoop(ctx, entity, pipeline, stream, &wo, events)
+
+	return events, nil
+}
+
+func (clt *Client) openChangeStream(ctx context.Context, entity string, pipeline []bson.M, wo *watchOptions) (*mongo.ChangeStream, error) {
+
+	csOpts := options.ChangeStream()
+	if wo.resumeToken != nil {
+		csOpts = csOpts.SetResumeAfter(wo.resumeToken)
+	}
+	if wo.startAtOperationTime != nil {
+		csOpts = csOpts.SetStartAtOperationTime(wo.startAtOperationTime)
+	}
+	if wo.fullDocument {
+		csOpts = csOpts.SetFullDocument(options.UpdateLookup)
+	}
+
+	mpipeline := mongo.Pipeline{}
+	for _, stage := range pipeline {
+		d := bson.D{}
+		for k, v := range stage {
+			d = append(d, bson.E{Key: k, Value: v})
+		}
+		mpipeline = append(mpipeline, d)
+	}
+
+	db := clt.client.Database(clt.opts.dbName)
+	if entity == "" {
+		return db.Watch(ctx, mpipeline, csOpts)
+	}
+	return db.Collection(entity).Watch(ctx, mpipeline, csOpts)
+}
+
+// watchLoop delivers decoded events to events until ctx is cancelled or the
+// stream closes, resuming the stream on retryable failures
+func (clt *Client) watchLoop(ctx context.Context, entity string, pipeline []bson.M, stream *mongo.ChangeStream, wo *watchOptions, events chan<- ChangeEvent) {
+
+	defer close(events)
+	defer stream.Close(ctx)
+
+	for {
+		for stream.Next(ctx) {
+			var raw bson.M
+			if err := stream.Decode(&raw); err != nil {
+				continue
+			}
+
+			event := make(ChangeEvent, len(raw))
+			for key, value := range raw {
+				event[key] = convertToNative(value)
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		if err := stream.Err(); err != nil {
+			if ctx.Err() != nil || !isResumableError(err) {
+				return
+			}
+
+			// the driver itself retries resumable errors internally; this is a
+			// fallback for the case where the underlying stream was dropped.
+			// Carry forward the caller's original options (ex: fullDocument)
+			// rather than dropping them, only replacing the resume position.
+			stream.Close(ctx)
+			resumeOpts := *wo
+			resumeOpts.resumeToken = stream.ResumeToken()
+			resumeOpts.startAtOperationTime = nil
+			resumed, rerr := clt.openChangeStream(ctx, entity, pipeline, &resumeOpts)
+			if rerr != nil {
+				return
+			}
+			stream = resumed
+			wo = &resumeOpts
+			continue
+		}
+
+		return
+	}
+}
+
+// isResumableError reports whether err carries mongo's "ResumableChangeStreamError" label
+func isResumableError(err error) bool {
+	if le, ok := err.(interface{ HasErrorLabel(string) bool }); ok {
+		return le.HasErrorLabel("ResumableChangeStreamError")
+	}
+	return false
+}